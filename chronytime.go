@@ -9,9 +9,13 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
 	"net"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 	"unsafe"
 )
@@ -20,17 +24,27 @@ var networkOrder = binary.BigEndian
 
 // #defines from chrony/candm.c
 const (
-	// UNIX domain socket might be available if we are running as chrony user or
-	// root, but regular users will connect via UDP
-	// const defaultCommandSocket = "/var/run/chrony/chronyd.sock"
-	defaultCandMPort = 323
+	// defaultUnixSocket is available if we are running as the chrony user or
+	// root. Regular users will connect via UDP instead.
+	defaultUnixSocket = "/var/run/chrony/chronyd.sock"
+	defaultCandMPort  = 323
+
+	// defaultReadTimeout bounds how long a request waits for chronyd to reply.
+	defaultReadTimeout = 1 * time.Second
 
 	// Packet types (request.pktType and response.PktType)
 	pktTypeCmdRequest = 1
 	pktTypeCmdReply   = 2
 
 	// Commands (request.command and response.Command)
-	cmdTracking = 33 // also used for waitSync
+	cmdNSources    = 14
+	cmdSourceData  = 15
+	cmdTracking    = 33 // also used for waitSync
+	cmdSourceStats = 34
+	cmdActivity    = 44
+	cmdServerStats = 54
+	cmdNTPData     = 57
+	cmdSelectData  = 69
 
 	// Replies (response.Reply)
 	rpyNull     = 1
@@ -63,9 +77,12 @@ type ipAddr struct {
 	Padding uint16
 }
 
-/* 32-bit floating-point format consisting of 7-bit signed exponent
-   and 25-bit signed coefficient without hidden bit.
-   The result is calculated as: 2^(exp - 25) * coef */
+/*
+32-bit floating-point format consisting of 7-bit signed exponent
+
+	and 25-bit signed coefficient without hidden bit.
+	The result is calculated as: 2^(exp - 25) * coef
+*/
 type cfloat struct {
 	F int32
 }
@@ -92,6 +109,12 @@ func (f *cfloat) value() float64 {
 	return float64(coef) * math.Pow(2.0, float64(exp))
 }
 
+// duration converts a cfloat holding a quantity of seconds, as most chrony
+// replies do, into a time.Duration.
+func (f *cfloat) duration() time.Duration {
+	return time.Duration(f.value() * float64(time.Second))
+}
+
 type request struct {
 	version  uint8
 	pktType  uint8
@@ -136,7 +159,7 @@ func uncertainty(r trackingResponse) time.Duration {
 	rootDelay := r.RootDelay.value()
 	rootDispersion := r.RootDispersion.value()
 	s := math.Abs(correction) + rootDispersion + (0.5 * rootDelay)
-	return time.Duration(s) * time.Second
+	return time.Duration(s * float64(time.Second))
 }
 
 type response struct {
@@ -158,27 +181,176 @@ type response struct {
 	Tracking trackingResponse
 }
 
-// Client is a chronytime client.
-type Client struct {
-	addr *net.UDPAddr
-	conn *net.UDPConn
+// replyHeader is the fixed-size portion common to every chronyd reply; the
+// command-specific payload immediately follows it in the wire format. It
+// mirrors the leading fields of response above, which is kept as-is for
+// backwards compatibility with the tracking-only API.
+type replyHeader struct {
+	Version  uint8
+	PktType  uint8
+	Res1     uint8
+	Res2     uint8
+	Command  uint16
+	Reply    uint16
+	Status   uint16
+	Pad1     uint16
+	Pad2     uint16
+	Pad3     uint16
+	Sequence uint32
+	Pad4     uint32
+	Pad5     uint32
 }
 
-// NewClient creates a new chronytime client and attempts to connect to a local
-// chronyd instance.
-func NewClient() (*Client, error) {
-	s, err := net.ResolveUDPAddr("udp4", fmt.Sprintf("127.0.0.1:%d", defaultCandMPort))
-	conn, err := net.DialUDP("udp4", nil, s)
-	if err != nil {
+// indexRequest selects a single source by its chronyd-assigned index, used
+// by commands such as Source, SourceStats and SelectData.
+type indexRequest struct {
+	Index int32
+}
+
+// NSourcesResponse reports how many sources chronyd is currently polling.
+type NSourcesResponse struct {
+	NSources int32
+}
+
+// SourceResponse is the per-source reply to the Source command.
+type SourceResponse struct {
+	Addr           ipAddr
+	Poll           int16
+	Stratum        uint16
+	State          uint16
+	Mode           uint16
+	Flags          uint16
+	Reachability   uint16
+	SinceSample    uint32
+	OrigLatestMeas cfloat
+	LatestMeas     cfloat
+	LatestMeasErr  cfloat
+}
+
+// SourceStatsResponse is the per-source reply to the SourceStats command.
+type SourceStatsResponse struct {
+	RefID              uint32
+	Addr               ipAddr
+	NSamples           uint32
+	NRuns              uint32
+	SpanSeconds        uint32
+	StandardDeviation  cfloat
+	ResidFreqPPM       cfloat
+	SkewPPM            cfloat
+	EstimatedOffset    cfloat
+	EstimatedOffsetErr cfloat
+}
+
+// ActivityResponse reports how many sources are online, offline, or
+// otherwise unreachable.
+type ActivityResponse struct {
+	Online       int32
+	Offline      int32
+	BurstOnline  int32
+	BurstOffline int32
+	Unresolved   int32
+}
+
+// ServerStatsResponse reports packet counts handled by chronyd's NTP and
+// command servers.
+type ServerStatsResponse struct {
+	NTPHits      uint32
+	CommandHits  uint32
+	NTPDrops     uint32
+	CommandDrops uint32
+	LogDrops     uint32
+}
+
+// NTPDataResponse is the reply to the NTPData command, describing the most
+// recent NTP exchange with a given source.
+type NTPDataResponse struct {
+	RemoteAddr      ipAddr
+	LocalAddr       ipAddr
+	RemotePort      uint16
+	Leap            uint16
+	Version         uint16
+	Mode            uint16
+	Stratum         uint16
+	Poll            int16
+	Precision       int16
+	RootDelay       cfloat
+	RootDispersion  cfloat
+	RefID           uint32
+	RefTime         timeSpec
+	Offset          cfloat
+	PeerDelay       cfloat
+	PeerDispersion  cfloat
+	ResponseTime    cfloat
+	JitterAsymmetry cfloat
+	Flags           uint32
+	TXTimestamping  uint8
+	RXTimestamping  uint8
+	TotalTxCount    uint32
+	TotalRxCount    uint32
+	TotalValidCount uint32
+}
+
+// SelectDataResponse is the per-source reply to the SelectData command,
+// describing how chronyd's source selection algorithm treats a source.
+type SelectDataResponse struct {
+	RefID          uint32
+	Addr           ipAddr
+	State          uint8
+	Authenticated  uint8
+	Reachability   uint16
+	LastSampleAgo  int32
+	OrigLatestMeas cfloat
+}
+
+// Network selects the transport a Client uses to reach chronyd.
+type Network string
+
+const (
+	// NetworkUDP4 dials chronyd's command port over IPv4 UDP. This is the
+	// default and works for any user, but modern chrony configs disable it.
+	NetworkUDP4 Network = "udp4"
+	// NetworkUDP6 dials chronyd's command port over IPv6 UDP.
+	NetworkUDP6 Network = "udp6"
+	// NetworkUnix connects to chronyd's UNIX domain socket, which requires
+	// running as root or the chrony user but is enabled by default.
+	NetworkUnix Network = "unix"
+)
+
+// transport sends a chrony command packet and returns the raw reply bytes.
+// It abstracts over UDP and UNIX domain socket connections so the rest of
+// the client can speak either one identically.
+type transport interface {
+	roundTrip(req []byte) ([]byte, error)
+	io.Closer
+}
+
+type udpTransport struct {
+	conn    *net.UDPConn
+	addr    *net.UDPAddr
+	timeout time.Duration
+}
+
+func (t *udpTransport) roundTrip(req []byte) ([]byte, error) {
+	if _, err := t.conn.Write(req); err != nil {
 		return nil, err
 	}
-	c := &Client{addr: s, conn: conn}
-
-	if err := c.waitSync(); err != nil {
+	buffer := make([]byte, 1024)
+	t.conn.SetDeadline(time.Now().Add(t.timeout))
+	n, addr, err := t.conn.ReadFromUDP(buffer)
+	if n == 0 {
+		return nil, fmt.Errorf("empty read")
+	}
+	if !sameUDPAddr(*addr, *t.addr) {
+		return nil, fmt.Errorf("expected %+v, got %+v", *t.addr, *addr)
+	}
+	if err != nil {
 		return nil, err
 	}
+	return buffer[:n], nil
+}
 
-	return c, nil
+func (t *udpTransport) Close() error {
+	return t.conn.Close()
 }
 
 func sameUDPAddr(a1, a2 net.UDPAddr) bool {
@@ -190,6 +362,162 @@ func sameUDPAddr(a1, a2 net.UDPAddr) bool {
 	return false
 }
 
+// unixTransport talks to chronyd's UNIX domain command socket, which
+// chronyd binds as SOCK_DGRAM rather than SOCK_STREAM. Like chronyc, this
+// requires binding our own client path so chronyd has somewhere to send its
+// reply datagram back to; chronyd authorizes the request by that socket
+// file's permissions, not by any credentials passed on the wire.
+type unixTransport struct {
+	conn      *net.UnixConn
+	localPath string
+	timeout   time.Duration
+}
+
+func (t *unixTransport) roundTrip(req []byte) ([]byte, error) {
+	if _, err := t.conn.Write(req); err != nil {
+		return nil, err
+	}
+	buffer := make([]byte, 1024)
+	t.conn.SetDeadline(time.Now().Add(t.timeout))
+	n, err := t.conn.Read(buffer)
+	if n == 0 {
+		return nil, fmt.Errorf("empty read")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buffer[:n], nil
+}
+
+func (t *unixTransport) Close() error {
+	err := t.conn.Close()
+	os.Remove(t.localPath)
+	return err
+}
+
+// bindUnixgramClient returns a fresh address in the temp directory for the
+// client end of a unixgram transport to chronyd. It does not bind the socket
+// itself; the caller's DialUnix binds it so chronyd can reply to it.
+func bindUnixgramClient() (*net.UnixAddr, error) {
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("chronytime-%d-%d.sock", os.Getpid(), rand.Uint32()))
+	return &net.UnixAddr{Name: path, Net: "unixgram"}, nil
+}
+
+// options holds the configuration assembled by a NewClientWithOptions call.
+type options struct {
+	network    Network
+	address    string
+	timeout    time.Duration
+	minRefresh time.Duration
+}
+
+func defaultOptions() options {
+	return options{
+		network: NetworkUDP4,
+		address: fmt.Sprintf("127.0.0.1:%d", defaultCandMPort),
+		timeout: defaultReadTimeout,
+	}
+}
+
+// Option configures a Client created by NewClientWithOptions.
+type Option func(*options)
+
+// WithNetwork selects the transport used to reach chronyd. The default is
+// NetworkUDP4; use NetworkUnix to talk to defaultUnixSocket (or a path set
+// via WithAddress) when running as root or the chrony user.
+func WithNetwork(n Network) Option {
+	return func(o *options) { o.network = n }
+}
+
+// WithAddress overrides the address Client dials: a host:port for
+// NetworkUDP4/NetworkUDP6, or a socket path for NetworkUnix. If unset, a
+// NetworkUnix client dials defaultUnixSocket.
+func WithAddress(addr string) Option {
+	return func(o *options) { o.address = addr }
+}
+
+// WithTimeout overrides how long a request waits for chronyd to reply.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+// WithMinRefresh caches Tracking/WaitUntilAfter reads for d, so concurrent
+// callers within d of the previous fetch reuse it instead of each issuing
+// their own round trip to chronyd. The default is 0: every call fetches
+// fresh. A single Client with WithMinRefresh set can be handed to multiple
+// metrics.Collector/RegisterOTEL registrations, and to application code
+// calling WaitUntilAfter directly, and all of them will share the cache.
+func WithMinRefresh(d time.Duration) Option {
+	return func(o *options) { o.minRefresh = d }
+}
+
+// Client is a chronytime client.
+type Client struct {
+	transport transport
+	pipeline  *Pipeline
+
+	minRefresh time.Duration
+
+	mu        sync.Mutex
+	cached    *response
+	fetchedAt time.Time
+	cachedErr error
+}
+
+// NewClient creates a new chronytime client and attempts to connect to a local
+// chronyd instance over UDP.
+func NewClient() (*Client, error) {
+	return NewClientWithOptions()
+}
+
+// NewClientWithOptions creates a new chronytime client using the given
+// Options, allowing callers to select the UDP or UNIX domain socket
+// transport, a custom address or socket path, and a custom read timeout.
+func NewClientWithOptions(opts ...Option) (*Client, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var t transport
+	switch o.network {
+	case NetworkUnix:
+		addr := o.address
+		if addr == "" {
+			addr = defaultUnixSocket
+		}
+		local, err := bindUnixgramClient()
+		if err != nil {
+			return nil, err
+		}
+		conn, err := net.DialUnix("unixgram", local, &net.UnixAddr{Name: addr, Net: "unixgram"})
+		if err != nil {
+			os.Remove(local.Name)
+			return nil, err
+		}
+		t = &unixTransport{conn: conn, localPath: local.Name, timeout: o.timeout}
+	case NetworkUDP4, NetworkUDP6:
+		s, err := net.ResolveUDPAddr(string(o.network), o.address)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := net.DialUDP(string(o.network), nil, s)
+		if err != nil {
+			return nil, err
+		}
+		t = &udpTransport{conn: conn, addr: s, timeout: o.timeout}
+	default:
+		return nil, fmt.Errorf("unsupported network %q", o.network)
+	}
+
+	c := &Client{transport: t, minRefresh: o.minRefresh}
+	c.pipeline = NewPipeline(c)
+	if err := c.waitSync(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func (c *Client) waitSync() error {
 	attempts := 0
 	maxAttempts := 3
@@ -198,7 +526,9 @@ func (c *Client) waitSync() error {
 		if attempts > maxAttempts {
 			return fmt.Errorf("max attempts exceeded waiting for sync")
 		}
-		r, err := c.trackingRequest()
+		// Bypass the minRefresh cache: each attempt must observe chronyd's
+		// current state, not a reading left over from a previous attempt.
+		r, err := c.fetchTracking()
 		if err != nil {
 			return err
 		}
@@ -216,47 +546,194 @@ func (c *Client) waitSync() error {
 	return nil
 }
 
+// fetchTracking issues a fresh tracking request to chronyd, bypassing the
+// minRefresh cache.
+func (c *Client) fetchTracking() (*response, error) {
+	rep := new(response)
+	if _, err := c.do(cmdTracking, nil, &rep.Tracking); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// trackingRequest returns a tracking reading, reusing one fetched within the
+// last minRefresh instead of issuing a new request if minRefresh is set.
 func (c *Client) trackingRequest() (*response, error) {
+	if c.minRefresh <= 0 {
+		return c.fetchTracking()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.fetchedAt) < c.minRefresh {
+		return c.cached, c.cachedErr
+	}
+	c.cached, c.cachedErr = c.fetchTracking()
+	c.fetchedAt = time.Now()
+	return c.cached, c.cachedErr
+}
+
+// TrackingResponse is the decoded, exported form of chronyd's tracking
+// reply, as reported by `chronyc tracking`.
+type TrackingResponse struct {
+	RefID             uint32
+	Stratum           uint16
+	LeapStatus        uint16
+	RefTime           time.Time
+	CurrentCorrection time.Duration
+	LastOffset        time.Duration
+	RmsOffset         time.Duration
+	FreqPPM           float64
+	SkewPPM           float64
+	RootDelay         time.Duration
+	RootDispersion    time.Duration
+	Uncertainty       time.Duration
+}
+
+// Tracking returns chronyd's current clock tracking statistics, as reported
+// by `chronyc tracking`.
+func (c *Client) Tracking() (TrackingResponse, error) {
+	rep, err := c.trackingRequest()
+	if err != nil {
+		return TrackingResponse{}, err
+	}
+	t := rep.Tracking
+	return TrackingResponse{
+		RefID:             t.RefID,
+		Stratum:           t.Stratum,
+		LeapStatus:        t.LeapStatus,
+		RefTime:           t.RefTime.Time(),
+		CurrentCorrection: t.CurrentCorrection.duration(),
+		LastOffset:        t.LastOffset.duration(),
+		RmsOffset:         t.RmsOffset.duration(),
+		FreqPPM:           t.FreqPPM.value(),
+		SkewPPM:           t.SkewPPM.value(),
+		RootDelay:         t.RootDelay.duration(),
+		RootDispersion:    t.RootDispersion.duration(),
+		Uncertainty:       uncertainty(t),
+	}, nil
+}
+
+// do sends a chrony command, optionally carrying a command-specific request
+// payload (written into the request's union region), and decodes the reply
+// payload following the header into out. out may be nil for commands whose
+// reply carries no additional data.
+//
+// do is the common plumbing behind trackingRequest and the typed read-only
+// command wrappers below (NumSources, Source, SourceStats, Activity,
+// ServerStats, NTPData, SelectData).
+func (c *Client) do(cmd uint16, payload interface{}, out interface{}) (replyHeader, error) {
 	r := request{
 		version:  6,
 		pktType:  pktTypeCmdRequest,
-		command:  cmdTracking,
+		command:  cmd,
 		attempt:  0,
 		sequence: rand.Uint32(),
 	}
-	if err := binary.Write(c.conn, networkOrder, r); err != nil {
-		return nil, err
+	if payload != nil {
+		var pbuf bytes.Buffer
+		if err := binary.Write(&pbuf, networkOrder, payload); err != nil {
+			return replyHeader{}, err
+		}
+		if pbuf.Len() > len(r.pad2) {
+			return replyHeader{}, fmt.Errorf("payload too large: %d bytes", pbuf.Len())
+		}
+		copy(r.pad2[:], pbuf.Bytes())
 	}
-	buffer := make([]byte, 1024)
-	rep := new(response)
-	c.conn.SetDeadline(time.Now().Add(1 * time.Second))
-	n, addr, err := c.conn.ReadFromUDP(buffer)
-	if n == 0 {
-		return nil, fmt.Errorf("empty read")
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, networkOrder, r); err != nil {
+		return replyHeader{}, err
 	}
 
 	// TODO: handle partial reads in a loop
+	reply, err := c.transport.roundTrip(buf.Bytes())
+	if err != nil {
+		return replyHeader{}, err
+	}
 
-	if !sameUDPAddr(*addr, *c.addr) {
-		return nil, fmt.Errorf("expected %+v, got %+v", *c.addr, *addr)
+	reader := bytes.NewReader(reply)
+	var hdr replyHeader
+	if err := binary.Read(reader, networkOrder, &hdr); err != nil {
+		return replyHeader{}, err
 	}
-	if err != nil {
-		return nil, err
+	if hdr.Sequence != r.sequence {
+		return replyHeader{}, fmt.Errorf("expected sequence %d, got %d", r.sequence, hdr.Sequence)
 	}
-	reader := bytes.NewReader(buffer)
-	if err := binary.Read(reader, networkOrder, rep); err != nil {
-		return nil, err
+	if hdr.Status != sttSuccess {
+		return hdr, fmt.Errorf("chronyd returned status %d", hdr.Status)
 	}
-	if rep.Sequence != r.sequence {
-		return nil, fmt.Errorf("expected sequence %d, got %d", r.sequence, rep.Sequence)
+	if out != nil {
+		if err := binary.Read(reader, networkOrder, out); err != nil {
+			return hdr, err
+		}
 	}
 
-	return rep, nil
+	return hdr, nil
+}
+
+// NumSources returns the number of sources chronyd is currently polling.
+func (c *Client) NumSources() (int, error) {
+	var rep NSourcesResponse
+	if _, err := c.do(cmdNSources, nil, &rep); err != nil {
+		return 0, err
+	}
+	return int(rep.NSources), nil
+}
+
+// Source returns the status of the source at the given chronyd-assigned
+// index, as reported by `chronyc sources`. Valid indices range over
+// [0, NumSources()).
+func (c *Client) Source(index int) (SourceResponse, error) {
+	var rep SourceResponse
+	_, err := c.do(cmdSourceData, &indexRequest{Index: int32(index)}, &rep)
+	return rep, err
+}
+
+// SourceStats returns the drift and offset statistics chronyd has
+// accumulated for the source at the given index, as reported by
+// `chronyc sourcestats`.
+func (c *Client) SourceStats(index int) (SourceStatsResponse, error) {
+	var rep SourceStatsResponse
+	_, err := c.do(cmdSourceStats, &indexRequest{Index: int32(index)}, &rep)
+	return rep, err
+}
+
+// Activity reports how many of chronyd's sources are currently online,
+// offline, or unresolved, as reported by `chronyc activity`.
+func (c *Client) Activity() (ActivityResponse, error) {
+	var rep ActivityResponse
+	_, err := c.do(cmdActivity, nil, &rep)
+	return rep, err
+}
+
+// ServerStats reports packet counts handled by chronyd's NTP and command
+// servers, as reported by `chronyc serverstats`.
+func (c *Client) ServerStats() (ServerStatsResponse, error) {
+	var rep ServerStatsResponse
+	_, err := c.do(cmdServerStats, nil, &rep)
+	return rep, err
+}
+
+// NTPData returns details of the most recent NTP exchange with the source
+// at the given index, as reported by `chronyc ntpdata`.
+func (c *Client) NTPData(index int) (NTPDataResponse, error) {
+	var rep NTPDataResponse
+	_, err := c.do(cmdNTPData, &indexRequest{Index: int32(index)}, &rep)
+	return rep, err
+}
+
+// SelectData reports how chronyd's source selection algorithm is treating
+// the source at the given index, as reported by `chronyc selectdata`.
+func (c *Client) SelectData(index int) (SelectDataResponse, error) {
+	var rep SelectDataResponse
+	_, err := c.do(cmdSelectData, &indexRequest{Index: int32(index)}, &rep)
+	return rep, err
 }
 
 // Close closes the client.
 func (c *Client) Close() error {
-	return c.conn.Close()
+	return c.transport.Close()
 }
 
 // WaitUntilAfter blocks until chronytime is sure the current time is after t.
@@ -301,6 +778,10 @@ type CommitFunc func(time.Time) error
 //
 // To ensure consistency, success should not be reported to any external clients until after
 // ConsistentOperation has returned.
+//
+// Concurrent calls to ConsistentOperation on the same Client share a single
+// background sleeper via Client.pipeline, so throughput under concurrency
+// scales close to 1/uncertainty rather than 1/(uncertainty * N).
 func (c *Client) ConsistentOperation(prepare PrepareFunc, commit CommitFunc) (time.Time, error) {
 	if err := prepare(); err != nil {
 		return time.Time{}, err
@@ -308,7 +789,7 @@ func (c *Client) ConsistentOperation(prepare PrepareFunc, commit CommitFunc) (ti
 	t := time.Now()
 	var finished = make(chan struct{})
 	go func() {
-		c.WaitUntilAfter(t)
+		c.pipeline.Wait(t)
 		close(finished)
 	}()
 	if err := commit(t); err != nil {
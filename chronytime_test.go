@@ -4,11 +4,10 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 	"time"
-
-	"gonum.org/v1/gonum/floats"
 )
 
 func TestClient(t *testing.T) {
@@ -43,12 +42,23 @@ func TestCFloat(t *testing.T) {
 	}
 	for _, c := range cases {
 		cf := cfloat{F: c.f}
-		if !floats.EqualWithinULP(c.want, cf.value(), c.ulp) {
+		if !equalWithinULP(c.want, cf.value(), c.ulp) {
 			t.Errorf("Want: %f, got %f", c.want, cf.value())
 		}
 	}
 }
 
+// equalWithinULP reports whether a and b differ by no more than ulp units
+// in the last place.
+func equalWithinULP(a, b float64, ulp uint) bool {
+	if a == b {
+		return true
+	}
+	steps := (b - a) / (math.Nextafter(a, b) - a)
+	usteps := uint(math.Trunc(steps))
+	return usteps <= ulp
+}
+
 func TestResponseParse(t *testing.T) {
 	/*
 		$ strace -f -e trace=network -x -s 10000 chronyc tracking
@@ -101,7 +111,6 @@ func TestResponseParse(t *testing.T) {
 			RootDelay:          cfloat{F: -154422419},
 			RootDispersion:     cfloat{F: -254273351},
 			LastUpdateInterval: cfloat{F: 411118241},
-			EOR:                0,
 		},
 	}
 	//reader := &zeroReader{r: bytes.NewReader(testVec)}
@@ -121,6 +130,65 @@ func TestResponseParse(t *testing.T) {
 	}
 }
 
+// TestActivityResponseWireFormat decodes a hand-assembled reply built
+// directly from chronyd's command-reply wire layout (replyHeader followed
+// by the ActivityResponse payload), independent of the ActivityResponse
+// struct it verifies, so a wrong field order or width would fail to decode
+// rather than trivially round-tripping through itself. It models 3 sources
+// online, 1 offline, and 2 unresolved.
+func TestActivityResponseWireFormat(t *testing.T) {
+	testVec := []byte("\x06\x02\x00\x00\x00\x2c\x00\x0c\x00\x00\x00\x00\x00\x00\x00\x00\xca\xfe\xf0\x0d\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x03\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x02")
+
+	reader := bytes.NewReader(testVec)
+	var hdr replyHeader
+	if err := binary.Read(reader, networkOrder, &hdr); err != nil {
+		t.Fatal(err)
+	}
+	wantHdr := replyHeader{Version: 6, PktType: pktTypeCmdReply, Command: cmdActivity, Reply: 12, Sequence: 0xCAFEF00D}
+	if hdr != wantHdr {
+		t.Fatalf("header: want %+v, got %+v", wantHdr, hdr)
+	}
+
+	var got ActivityResponse
+	if err := binary.Read(reader, networkOrder, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := ActivityResponse{Online: 3, Offline: 1, BurstOnline: 0, BurstOffline: 0, Unresolved: 2}
+	if got != want {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+// TestTrackingRequestMinRefresh confirms WithMinRefresh makes Tracking reuse
+// a cached reading within the window and fetch a fresh one once it expires.
+func TestTrackingRequestMinRefresh(t *testing.T) {
+	tr := &fakeTrackingTransport{tracking: trackingResponse{
+		Addr:    ipAddr{Family: ipAddrFamilyINET4},
+		RefID:   1,
+		Stratum: 1,
+	}}
+	c := &Client{transport: tr, minRefresh: time.Hour}
+
+	if _, err := c.Tracking(); err != nil {
+		t.Fatal(err)
+	}
+	callsAfterFirst := tr.calls
+	if _, err := c.Tracking(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.calls != callsAfterFirst {
+		t.Errorf("expected second Tracking call within minRefresh to reuse the cache, got %d round trips, want %d", tr.calls, callsAfterFirst)
+	}
+
+	c.fetchedAt = time.Now().Add(-2 * time.Hour)
+	if _, err := c.Tracking(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.calls != callsAfterFirst+1 {
+		t.Errorf("expected Tracking to refetch once the cache expired, got %d round trips, want %d", tr.calls, callsAfterFirst+1)
+	}
+}
+
 func TestConsistenOperation(t *testing.T) {
 	c, err := NewClient()
 	if err != nil {
@@ -160,3 +228,25 @@ func TestConsistenOperation(t *testing.T) {
 		t.Errorf("expected time.Time zero value, got %v", cots)
 	}
 }
+
+// BenchmarkConsistentOperation runs ConsistentOperation at increasing
+// concurrency to demonstrate that the shared Pipeline keeps throughput close
+// to 1/uncertainty rather than degrading to 1/(uncertainty * N).
+func BenchmarkConsistentOperation(b *testing.B) {
+	c, err := NewClient()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer c.Close()
+
+	noop := func() error { return nil }
+	commit := func(time.Time) error { return nil }
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.ConsistentOperation(noop, commit); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,169 @@
+// Package metrics exposes a chronytime.Client's tracking statistics as
+// Prometheus and OpenTelemetry metrics, suitable for building chrony health
+// dashboards and alerts.
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/vsekhar/chronytime"
+)
+
+const namespace = "chrony"
+
+// Collector adapts a *chronytime.Client into a prometheus.Collector. It does
+// no caching of its own: construct client with chronytime.WithMinRefresh to
+// have scrapes, other Collector/RegisterOTEL registrations over the same
+// Client, and application code calling client.WaitUntilAfter or
+// client.Tracking directly all share a single chronyd round trip per
+// refresh window.
+type Collector struct {
+	client *chronytime.Client
+
+	uncertainty       *prometheus.Desc
+	currentCorrection *prometheus.Desc
+	lastOffset        *prometheus.Desc
+	rmsOffset         *prometheus.Desc
+	rootDelay         *prometheus.Desc
+	rootDispersion    *prometheus.Desc
+	frequencyPPM      *prometheus.Desc
+	skewPPM           *prometheus.Desc
+	stratum           *prometheus.Desc
+	leapStatus        *prometheus.Desc
+	refTime           *prometheus.Desc
+}
+
+// NewCollector returns a Collector wrapping client. To bound how often it
+// hits chronyd, construct client with chronytime.WithMinRefresh.
+func NewCollector(client *chronytime.Client) *Collector {
+	return &Collector{
+		client: client,
+
+		uncertainty:       prometheus.NewDesc(namespace+"_tracking_uncertainty_seconds", "Estimated uncertainty in chronyd's current clock correction.", nil, nil),
+		currentCorrection: prometheus.NewDesc(namespace+"_current_correction_seconds", "Current correction applied to the system clock.", nil, nil),
+		lastOffset:        prometheus.NewDesc(namespace+"_last_offset_seconds", "Estimated offset of the last clock update.", nil, nil),
+		rmsOffset:         prometheus.NewDesc(namespace+"_rms_offset_seconds", "Long-term RMS average of the clock offset.", nil, nil),
+		rootDelay:         prometheus.NewDesc(namespace+"_root_delay_seconds", "Total network delay to the stratum-1 reference clock.", nil, nil),
+		rootDispersion:    prometheus.NewDesc(namespace+"_root_dispersion_seconds", "Total dispersion accumulated through the chain to the stratum-1 reference clock.", nil, nil),
+		frequencyPPM:      prometheus.NewDesc(namespace+"_frequency_ppm", "Rate at which the system clock is adjusted relative to its true rate.", nil, nil),
+		skewPPM:           prometheus.NewDesc(namespace+"_skew_ppm", "Estimated error bound on the frequency.", nil, nil),
+		stratum:           prometheus.NewDesc(namespace+"_stratum", "Stratum of the reference chronyd is synchronized to.", nil, nil),
+		leapStatus:        prometheus.NewDesc(namespace+"_leap_status", "Pending leap second: 0 normal, 1 insert, 2 delete, 3 not synchronized.", nil, nil),
+		refTime:           prometheus.NewDesc(namespace+"_ref_time_seconds", "Unix time at which the last clock measurement was made.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.uncertainty
+	ch <- c.currentCorrection
+	ch <- c.lastOffset
+	ch <- c.rmsOffset
+	ch <- c.rootDelay
+	ch <- c.rootDispersion
+	ch <- c.frequencyPPM
+	ch <- c.skewPPM
+	ch <- c.stratum
+	ch <- c.leapStatus
+	ch <- c.refTime
+}
+
+// Collect implements prometheus.Collector. Scrape errors are dropped rather
+// than surfaced, matching the rest of the prometheus.Collector ecosystem.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	t, err := c.client.Tracking()
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.uncertainty, prometheus.GaugeValue, t.Uncertainty.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.currentCorrection, prometheus.GaugeValue, t.CurrentCorrection.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.lastOffset, prometheus.GaugeValue, t.LastOffset.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.rmsOffset, prometheus.GaugeValue, t.RmsOffset.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.rootDelay, prometheus.GaugeValue, t.RootDelay.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.rootDispersion, prometheus.GaugeValue, t.RootDispersion.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.frequencyPPM, prometheus.GaugeValue, t.FreqPPM)
+	ch <- prometheus.MustNewConstMetric(c.skewPPM, prometheus.GaugeValue, t.SkewPPM)
+	ch <- prometheus.MustNewConstMetric(c.stratum, prometheus.GaugeValue, float64(t.Stratum))
+	ch <- prometheus.MustNewConstMetric(c.leapStatus, prometheus.GaugeValue, float64(t.LeapStatus))
+	ch <- prometheus.MustNewConstMetric(c.refTime, prometheus.GaugeValue, float64(t.RefTime.Unix()))
+}
+
+// RegisterOTEL registers observable gauges on meter reporting client's
+// tracking statistics. As with Collector, construct client with
+// chronytime.WithMinRefresh to bound how often it hits chronyd; doing so
+// also means this registration shares a round trip with any Collector or
+// other RegisterOTEL call over the same Client.
+// The returned metric.Registration can be used to unregister the callback.
+func RegisterOTEL(meter metric.Meter, client *chronytime.Client) (metric.Registration, error) {
+	c := &Collector{client: client}
+
+	uncertainty, err := meter.Float64ObservableGauge(namespace+"_tracking_uncertainty_seconds", metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	currentCorrection, err := meter.Float64ObservableGauge(namespace+"_current_correction_seconds", metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	lastOffset, err := meter.Float64ObservableGauge(namespace+"_last_offset_seconds", metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	rmsOffset, err := meter.Float64ObservableGauge(namespace+"_rms_offset_seconds", metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	rootDelay, err := meter.Float64ObservableGauge(namespace+"_root_delay_seconds", metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	rootDispersion, err := meter.Float64ObservableGauge(namespace+"_root_dispersion_seconds", metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	frequencyPPM, err := meter.Float64ObservableGauge(namespace + "_frequency_ppm")
+	if err != nil {
+		return nil, err
+	}
+	skewPPM, err := meter.Float64ObservableGauge(namespace + "_skew_ppm")
+	if err != nil {
+		return nil, err
+	}
+	stratum, err := meter.Int64ObservableGauge(namespace + "_stratum")
+	if err != nil {
+		return nil, err
+	}
+	leapStatus, err := meter.Int64ObservableGauge(namespace + "_leap_status")
+	if err != nil {
+		return nil, err
+	}
+	refTime, err := meter.Int64ObservableGauge(namespace+"_ref_time_seconds", metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		t, err := c.client.Tracking()
+		if err != nil {
+			return err
+		}
+		o.ObserveFloat64(uncertainty, t.Uncertainty.Seconds())
+		o.ObserveFloat64(currentCorrection, t.CurrentCorrection.Seconds())
+		o.ObserveFloat64(lastOffset, t.LastOffset.Seconds())
+		o.ObserveFloat64(rmsOffset, t.RmsOffset.Seconds())
+		o.ObserveFloat64(rootDelay, t.RootDelay.Seconds())
+		o.ObserveFloat64(rootDispersion, t.RootDispersion.Seconds())
+		o.ObserveFloat64(frequencyPPM, t.FreqPPM)
+		o.ObserveFloat64(skewPPM, t.SkewPPM)
+		o.ObserveInt64(stratum, int64(t.Stratum))
+		o.ObserveInt64(leapStatus, int64(t.LeapStatus))
+		o.ObserveInt64(refTime, t.RefTime.Unix())
+		return nil
+	},
+		uncertainty, currentCorrection, lastOffset, rmsOffset, rootDelay, rootDispersion,
+		frequencyPPM, skewPPM, stratum, leapStatus, refTime,
+	)
+}
@@ -0,0 +1,145 @@
+package chronytime
+
+import (
+	"sync"
+	"time"
+)
+
+// CommitWait blocks until chronytime is sure the wall clock is past t, the
+// same guarantee as WaitUntilAfter. Rather than polling chronyd on every
+// loop iteration, CommitWait sleeps once for an estimate of the remaining
+// uncertainty and then re-fetches only to verify, which keeps the number of
+// chronyd round trips to about two (one before sleeping, one to verify)
+// regardless of how close t is to now.
+func (c *Client) CommitWait(t time.Time) error {
+	r, err := c.Tracking()
+	if err != nil {
+		return err
+	}
+	if wait := time.Until(t) + r.Uncertainty; wait > 0 {
+		time.Sleep(wait)
+	}
+
+	for {
+		r, err := c.Tracking()
+		if err != nil {
+			return err
+		}
+		now := time.Now().Add(r.CurrentCorrection)
+		earliest := now.Add(-r.Uncertainty)
+		if earliest.After(t) {
+			return nil
+		}
+		time.Sleep(t.Sub(earliest))
+	}
+}
+
+// pendingWait is one caller's outstanding Pipeline.Wait call.
+type pendingWait struct {
+	target time.Time
+	done   chan error
+}
+
+// Pipeline batches concurrent commit-waits behind a single background
+// sleeper. Without it, N concurrent ConsistentOperation calls each sleep out
+// the full uncertainty window independently; with it, they share one
+// sleeper sized to the latest of their targets, so throughput under
+// concurrency scales close to 1/uncertainty rather than 1/(uncertainty*N).
+type Pipeline struct {
+	client *Client
+
+	mu       sync.Mutex
+	pending  []pendingWait
+	sleeping bool
+}
+
+// NewPipeline creates a Pipeline backed by client.
+func NewPipeline(client *Client) *Pipeline {
+	return &Pipeline{client: client}
+}
+
+// Wait blocks until the uncertainty window has certainly passed target. If a
+// sleeper is already running, this call joins it instead of starting a new
+// one; the running sleeper always waits for the maximum outstanding target.
+func (p *Pipeline) Wait(target time.Time) error {
+	done := make(chan error, 1)
+
+	p.mu.Lock()
+	p.pending = append(p.pending, pendingWait{target: target, done: done})
+	if !p.sleeping {
+		p.sleeping = true
+		go p.run()
+	}
+	p.mu.Unlock()
+
+	return <-done
+}
+
+// run is the background sleeper. It sleeps until the uncertainty window has
+// passed the largest target among the currently pending waiters, releases
+// every waiter whose target has been satisfied, and repeats for any
+// waiters that joined in the meantime, until the queue is empty.
+func (p *Pipeline) run() {
+	for {
+		p.mu.Lock()
+		if len(p.pending) == 0 {
+			p.sleeping = false
+			p.mu.Unlock()
+			return
+		}
+		maxTarget := p.pending[0].target
+		for _, w := range p.pending[1:] {
+			if w.target.After(maxTarget) {
+				maxTarget = w.target
+			}
+		}
+		p.mu.Unlock()
+
+		r, err := p.client.Tracking()
+		if err != nil {
+			p.drain(err)
+			return
+		}
+		now := time.Now().Add(r.CurrentCorrection)
+		earliest := now.Add(-r.Uncertainty)
+		if !earliest.After(maxTarget) {
+			time.Sleep(maxTarget.Sub(earliest))
+			continue
+		}
+
+		p.mu.Lock()
+		var satisfied, remaining []pendingWait
+		for _, w := range p.pending {
+			if earliest.After(w.target) {
+				satisfied = append(satisfied, w)
+			} else {
+				remaining = append(remaining, w)
+			}
+		}
+		p.pending = remaining
+		if len(remaining) == 0 {
+			p.sleeping = false
+		}
+		p.mu.Unlock()
+
+		for _, w := range satisfied {
+			w.done <- nil
+		}
+		if len(remaining) == 0 {
+			return
+		}
+	}
+}
+
+// drain delivers err to every pending waiter and empties the queue.
+func (p *Pipeline) drain(err error) {
+	p.mu.Lock()
+	batch := p.pending
+	p.pending = nil
+	p.sleeping = false
+	p.mu.Unlock()
+
+	for _, w := range batch {
+		w.done <- err
+	}
+}
@@ -0,0 +1,64 @@
+package chronytime
+
+import "time"
+
+// Admin commands, from chrony/candm.c. chronyd only accepts these over
+// NetworkUnix, where access is controlled by the command socket file's
+// permissions rather than anything carried in the request.
+const (
+	cmdBurst    = 24
+	cmdShutdown = 40
+	cmdSettime  = 49
+	cmdMakestep = 51
+)
+
+// burstRequest requests a burst of measurements from one or all sources, as
+// issued by `chronyc burst`.
+type burstRequest struct {
+	NGoodSamples  int32
+	NTotalSamples int32
+	Mask          ipAddr
+	Address       ipAddr
+}
+
+// Burst tells chronyd to take nTotal measurements from each currently
+// selectable source, keeping only the best nGood of them, as issued by
+// `chronyc burst <n-good>/<n-total>`.
+func (c *Client) Burst(nGood, nTotal int) error {
+	_, err := c.Do(Request{
+		Command: cmdBurst,
+		Payload: &burstRequest{NGoodSamples: int32(nGood), NTotalSamples: int32(nTotal)},
+	})
+	return err
+}
+
+// MakeStep tells chronyd to step the system clock to the current estimate
+// immediately, rather than slewing it, as issued by `chronyc makestep`.
+func (c *Client) MakeStep() error {
+	_, err := c.Do(Request{Command: cmdMakestep})
+	return err
+}
+
+// settimeRequest carries the time chronyd should set the system clock to.
+type settimeRequest struct {
+	Ts timeSpec
+}
+
+// SetTime tells chronyd to set the system clock to t, as issued by
+// `chronyc settime`. chronyd replies with rpyNull on success.
+func (c *Client) SetTime(t time.Time) error {
+	sec := t.Unix()
+	req := &settimeRequest{Ts: timeSpec{
+		SecHigh: uint32(sec >> 32),
+		SecLow:  uint32(sec),
+		Nsec:    uint32(t.Nanosecond()),
+	}}
+	_, err := c.Do(Request{Command: cmdSettime, Payload: req})
+	return err
+}
+
+// Shutdown tells chronyd to terminate, as issued by `chronyc shutdown`.
+func (c *Client) Shutdown() error {
+	_, err := c.Do(Request{Command: cmdShutdown})
+	return err
+}
@@ -12,7 +12,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"net"
+	"sync"
 	"time"
 )
 
@@ -20,9 +20,6 @@ var networkOrder = binary.BigEndian
 
 // #defines from chrony/candm.c
 const (
-	// UNIX domain socket might be available if we are running as chrony user or
-	// root, but regular users will connect via UDP
-	// const defaultCommandSocket = "/var/run/chrony/chronyd.sock"
 	defaultCandMPort = 323
 
 	// Packet types (request.pktType and response.PktType)
@@ -92,6 +89,14 @@ func (f *cfloat) value() float64 {
 	return float64(coef) * math.Pow(2.0, float64(exp))
 }
 
+// Value returns f decoded as a float64. Response structs such as
+// SourceStatsResponse expose raw cfloat fields rather than a converted copy;
+// Value lets callers outside this package decode them without needing to
+// name the unexported cfloat type.
+func (f *cfloat) Value() float64 {
+	return f.value()
+}
+
 type request struct {
 	version  uint8
 	pktType  uint8
@@ -132,9 +137,10 @@ type trackingResponse struct {
 
 func uncertaintyFromCorrectedTime(r trackingResponse) time.Duration {
 	// https://listengine.tuxfamily.org/chrony.tuxfamily.org/chrony-users/2017/08/msg00014.html
+	correction := r.CurrentCorrection.value()
 	rootDelay := r.RootDelay.value()
 	rootDispersion := r.RootDispersion.value()
-	s := rootDispersion + (0.5 * rootDelay)
+	s := math.Abs(correction) + rootDispersion + (0.5 * rootDelay)
 	ns := s * math.Pow(10, 9)
 	return time.Duration(ns)
 }
@@ -162,30 +168,35 @@ var responseBinarySize = binary.Size(response{})
 
 // Client is a chronytime client.
 type Client struct {
-	addr *net.UDPAddr
-	conn *net.UDPConn
+	transport transport
+
+	hlcMu       sync.Mutex
+	hlcPhysical uint64
+	hlcLogical  uint32
 }
 
-// NewClient creates a new chronytime client and attempts to connect to a local
-// chronyd instance.
+// NewClient creates a new chronytime client and attempts to connect to a
+// local chronyd instance over UDP.
 func NewClient() (*Client, error) {
-	s, err := net.ResolveUDPAddr("udp4", fmt.Sprintf("127.0.0.1:%d", defaultCandMPort))
-	conn, err := net.DialUDP("udp4", nil, s)
-	if err != nil {
-		return nil, err
-	}
-	c := &Client{addr: s, conn: conn}
-
-	return c, nil
+	return NewClientWithOptions()
 }
 
-func sameUDPAddr(a1, a2 net.UDPAddr) bool {
-	if a1.IP.Equal(a2.IP) &&
-		a1.Port == a2.Port &&
-		a1.Zone == a2.Zone {
-		return true
+// NewClientWithOptions creates a new chronytime client using the given
+// Options, allowing callers to select the UDP or UNIX domain socket
+// transport, a custom address or socket path, and a custom read timeout.
+// Use WithNetwork(NetworkUnix) (optionally with WithAddress) to reach
+// chronyd's command socket for privileged commands such as MakeStep, Burst,
+// SetTime and Shutdown, which chronyd refuses over UDP.
+func NewClientWithOptions(opts ...Option) (*Client, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	t, err := dialTransport(o)
+	if err != nil {
+		return nil, err
 	}
-	return false
+	return &Client{transport: t}, nil
 }
 
 // Response is a struct containing a time reading consisting of a timestamp and
@@ -230,29 +241,22 @@ func (c *Client) trackingRequest() (*response, error) {
 		attempt:  0,
 		sequence: rand.Uint32(),
 	}
-	if err := binary.Write(c.conn, networkOrder, r); err != nil {
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, networkOrder, r); err != nil {
 		return nil, err
 	}
-	buffer := make([]byte, 1024)
-	rep := new(response)
-	c.conn.SetDeadline(time.Now().Add(1 * time.Second))
-	n, addr, err := c.conn.ReadFromUDP(buffer)
-	if n == 0 {
-		return nil, fmt.Errorf("empty read")
-	}
-
-	if n < responseBinarySize {
-		// TODO: handle partial reads in a loop
-		return nil, fmt.Errorf("short read: expected %d bytes, got %d bytes", binary.Size(rep), n)
-	}
 
-	if !sameUDPAddr(*addr, *c.addr) {
-		return nil, fmt.Errorf("expected %+v, got %+v", *c.addr, *addr)
-	}
+	reply, err := c.transport.roundTrip(buf.Bytes())
 	if err != nil {
 		return nil, err
 	}
-	reader := bytes.NewReader(buffer)
+	if len(reply) < responseBinarySize {
+		// TODO: handle partial reads in a loop
+		return nil, fmt.Errorf("short read: expected %d bytes, got %d bytes", responseBinarySize, len(reply))
+	}
+
+	rep := new(response)
+	reader := bytes.NewReader(reply)
 	if err := binary.Read(reader, networkOrder, rep); err != nil {
 		return nil, err
 	}
@@ -265,7 +269,7 @@ func (c *Client) trackingRequest() (*response, error) {
 
 // Close closes the client.
 func (c *Client) Close() error {
-	return c.conn.Close()
+	return c.transport.Close()
 }
 
 // WaitUntilAfter blocks until chronytime is sure the current time is after t.
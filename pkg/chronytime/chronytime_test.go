@@ -114,7 +114,7 @@ var testRep = response{
 
 func TestUncertainty(t *testing.T) {
 	x := 8313231 * time.Nanosecond
-	if u := uncertainty(testRep.Tracking); u != x {
+	if u := uncertaintyFromCorrectedTime(testRep.Tracking); u != x {
 		t.Errorf("expected uncertainty %s, got %s", x.String(), u.String())
 	}
 }
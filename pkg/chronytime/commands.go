@@ -0,0 +1,353 @@
+package chronytime
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+)
+
+// Commands beyond Tracking, from chrony/candm.c.
+const (
+	cmdNSources    = 14
+	cmdSourceData  = 15
+	cmdManualList  = 20
+	cmdSourceStats = 34
+	cmdActivity    = 44
+	cmdServerStats = 54
+	cmdNTPData     = 57
+	cmdSmoothing   = 67
+)
+
+// maxDoAttempts bounds how many times in total Do sends a command, retrying
+// after a read timeout and incrementing the request's attempt counter each
+// time, the way chronyc itself does.
+const maxDoAttempts = 3
+
+// replyHeader is the fixed-size portion common to every chronyd reply; the
+// command-specific payload immediately follows it in the wire format.
+type replyHeader struct {
+	Version  uint8
+	PktType  uint8
+	Res1     uint8
+	Res2     uint8
+	Command  uint16
+	Reply    uint16
+	Status   uint16
+	Pad1     uint16
+	Pad2     uint16
+	Pad3     uint16
+	Sequence uint32
+	Pad4     uint32
+	Pad5     uint32
+}
+
+// indexRequest selects a single source by its chronyd-assigned index, used
+// by commands such as Sources, SourceStats and NTPData.
+type indexRequest struct {
+	Index int32
+}
+
+// Request describes a chrony command to send to chronyd, along with an
+// optional command-specific payload to embed in the request's union region.
+type Request struct {
+	Command uint16
+	Payload interface{}
+}
+
+// Reply is a decoded chronyd reply: the common header plus the raw
+// command-specific payload bytes. Use Decode to parse the payload into the
+// struct matching the command that produced it.
+type Reply struct {
+	Header replyHeader
+
+	payload []byte
+}
+
+// Decode parses the reply's command-specific payload into out, which must
+// be a pointer to a fixed-size struct matching the wire format for the
+// command that produced this Reply.
+func (r Reply) Decode(out interface{}) error {
+	return binary.Read(bytes.NewReader(r.payload), networkOrder, out)
+}
+
+// Do sends req to chronyd and returns its decoded reply. Read timeouts are
+// retried, incrementing the request's attempt counter on each retry, until
+// maxDoAttempts sends in total have been made, before giving up.
+func (c *Client) Do(req Request) (Reply, error) {
+	var lastErr error
+	for attempt := uint16(0); int(attempt) < maxDoAttempts; attempt++ {
+		rep, err := c.doOnce(req, attempt)
+		if err == nil {
+			return rep, nil
+		}
+		lastErr = err
+		netErr, ok := err.(net.Error)
+		if !ok || !netErr.Timeout() {
+			break
+		}
+	}
+	return Reply{}, lastErr
+}
+
+func (c *Client) doOnce(req Request, attempt uint16) (Reply, error) {
+	r := request{
+		version:  6,
+		pktType:  pktTypeCmdRequest,
+		command:  req.Command,
+		attempt:  attempt,
+		sequence: rand.Uint32(),
+	}
+	if req.Payload != nil {
+		var pbuf bytes.Buffer
+		if err := binary.Write(&pbuf, networkOrder, req.Payload); err != nil {
+			return Reply{}, err
+		}
+		if pbuf.Len() > len(r.pad2) {
+			return Reply{}, fmt.Errorf("payload too large: %d bytes", pbuf.Len())
+		}
+		copy(r.pad2[:], pbuf.Bytes())
+	}
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, networkOrder, r); err != nil {
+		return Reply{}, err
+	}
+
+	reply, err := c.transport.roundTrip(buf.Bytes())
+	if err != nil {
+		return Reply{}, err
+	}
+
+	reader := bytes.NewReader(reply)
+	var hdr replyHeader
+	if err := binary.Read(reader, networkOrder, &hdr); err != nil {
+		return Reply{}, err
+	}
+	if hdr.Sequence != r.sequence {
+		return Reply{}, fmt.Errorf("expected sequence %d, got %d", r.sequence, hdr.Sequence)
+	}
+	if hdr.Status != sttSuccess {
+		return Reply{}, fmt.Errorf("chronyd returned status %d", hdr.Status)
+	}
+
+	var payload []byte
+	if n := reader.Len(); n > 0 {
+		payload = make([]byte, n)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return Reply{}, err
+		}
+	}
+	return Reply{Header: hdr, payload: payload}, nil
+}
+
+// SourcesResponse is the per-source reply to the Sources command, as
+// reported by `chronyc sources`.
+type SourcesResponse struct {
+	Addr           ipAddr
+	Poll           int16
+	Stratum        uint16
+	State          uint16
+	Mode           uint16
+	Flags          uint16
+	Reachability   uint16
+	SinceSample    uint32
+	OrigLatestMeas cfloat
+	LatestMeas     cfloat
+	LatestMeasErr  cfloat
+}
+
+// NumSources returns how many sources chronyd is currently polling.
+func (c *Client) NumSources() (int, error) {
+	rep, err := c.Do(Request{Command: cmdNSources})
+	if err != nil {
+		return 0, err
+	}
+	var out struct{ NSources int32 }
+	if err := rep.Decode(&out); err != nil {
+		return 0, err
+	}
+	return int(out.NSources), nil
+}
+
+// Sources returns the status of the source at the given chronyd-assigned
+// index. Valid indices range over [0, NumSources()).
+func (c *Client) Sources(index int) (SourcesResponse, error) {
+	rep, err := c.Do(Request{Command: cmdSourceData, Payload: &indexRequest{Index: int32(index)}})
+	if err != nil {
+		return SourcesResponse{}, err
+	}
+	var out SourcesResponse
+	err = rep.Decode(&out)
+	return out, err
+}
+
+// SourceStatsResponse is the per-source reply to the SourceStats command,
+// as reported by `chronyc sourcestats`.
+type SourceStatsResponse struct {
+	RefID              uint32
+	Addr               ipAddr
+	NSamples           uint32
+	NRuns              uint32
+	SpanSeconds        uint32
+	StandardDeviation  cfloat
+	ResidFreqPPM       cfloat
+	SkewPPM            cfloat
+	EstimatedOffset    cfloat
+	EstimatedOffsetErr cfloat
+}
+
+// SourceStats returns the drift and offset statistics chronyd has
+// accumulated for the source at the given index.
+func (c *Client) SourceStats(index int) (SourceStatsResponse, error) {
+	rep, err := c.Do(Request{Command: cmdSourceStats, Payload: &indexRequest{Index: int32(index)}})
+	if err != nil {
+		return SourceStatsResponse{}, err
+	}
+	var out SourceStatsResponse
+	err = rep.Decode(&out)
+	return out, err
+}
+
+// ActivityResponse reports how many sources are online, offline, or
+// otherwise unreachable, as reported by `chronyc activity`.
+type ActivityResponse struct {
+	Online       int32
+	Offline      int32
+	BurstOnline  int32
+	BurstOffline int32
+	Unresolved   int32
+}
+
+// Activity reports how many of chronyd's sources are currently online,
+// offline, or unresolved.
+func (c *Client) Activity() (ActivityResponse, error) {
+	rep, err := c.Do(Request{Command: cmdActivity})
+	if err != nil {
+		return ActivityResponse{}, err
+	}
+	var out ActivityResponse
+	err = rep.Decode(&out)
+	return out, err
+}
+
+// ServerStatsResponse reports packet counts handled by chronyd's NTP and
+// command servers, as reported by `chronyc serverstats`.
+type ServerStatsResponse struct {
+	NTPHits      uint32
+	CommandHits  uint32
+	NTPDrops     uint32
+	CommandDrops uint32
+	LogDrops     uint32
+}
+
+// ServerStats reports packet counts handled by chronyd's NTP and command
+// servers.
+func (c *Client) ServerStats() (ServerStatsResponse, error) {
+	rep, err := c.Do(Request{Command: cmdServerStats})
+	if err != nil {
+		return ServerStatsResponse{}, err
+	}
+	var out ServerStatsResponse
+	err = rep.Decode(&out)
+	return out, err
+}
+
+// SmoothingResponse reports the state of chronyd's time smoothing, which
+// spreads out clock corrections to avoid sudden jumps in reported time, as
+// reported by `chronyc smoothing`.
+type SmoothingResponse struct {
+	RefTime       timeSpec
+	OffsetPPM     cfloat
+	FreqPPM       cfloat
+	WanderPPM     cfloat
+	LastUpdateAgo cfloat
+	RemainingTime cfloat
+	Active        uint32
+}
+
+// Smoothing reports the state of chronyd's time smoothing.
+func (c *Client) Smoothing() (SmoothingResponse, error) {
+	rep, err := c.Do(Request{Command: cmdSmoothing})
+	if err != nil {
+		return SmoothingResponse{}, err
+	}
+	var out SmoothingResponse
+	err = rep.Decode(&out)
+	return out, err
+}
+
+// NTPDataResponse is the reply to the NTPData command, describing the most
+// recent NTP exchange with a given source, as reported by `chronyc ntpdata`.
+type NTPDataResponse struct {
+	RemoteAddr      ipAddr
+	LocalAddr       ipAddr
+	RemotePort      uint16
+	Leap            uint16
+	Version         uint16
+	Mode            uint16
+	Stratum         uint16
+	Poll            int16
+	Precision       int16
+	RootDelay       cfloat
+	RootDispersion  cfloat
+	RefID           uint32
+	RefTime         timeSpec
+	Offset          cfloat
+	PeerDelay       cfloat
+	PeerDispersion  cfloat
+	ResponseTime    cfloat
+	JitterAsymmetry cfloat
+	Flags           uint32
+	TXTimestamping  uint8
+	RXTimestamping  uint8
+	TotalTxCount    uint32
+	TotalRxCount    uint32
+	TotalValidCount uint32
+}
+
+// NTPData returns details of the most recent NTP exchange with the source
+// at the given index.
+func (c *Client) NTPData(index int) (NTPDataResponse, error) {
+	rep, err := c.Do(Request{Command: cmdNTPData, Payload: &indexRequest{Index: int32(index)}})
+	if err != nil {
+		return NTPDataResponse{}, err
+	}
+	var out NTPDataResponse
+	err = rep.Decode(&out)
+	return out, err
+}
+
+// manualMaxSamples is the number of manual samples chronyd reports in a
+// single ManualList reply.
+const manualMaxSamples = 16
+
+// ManualSample is one reading accumulated by `chronyc manual`.
+type ManualSample struct {
+	When     timeSpec
+	Slewed   cfloat
+	Orig     cfloat
+	Residual cfloat
+}
+
+// ManualListResponse is the reply to the ManualList command, reporting the
+// samples accumulated by manual reference clock entry, as reported by
+// `chronyc manual list`.
+type ManualListResponse struct {
+	NSamples int32
+	Samples  [manualMaxSamples]ManualSample
+}
+
+// ManualList reports the samples accumulated by manual reference clock
+// entry.
+func (c *Client) ManualList() (ManualListResponse, error) {
+	rep, err := c.Do(Request{Command: cmdManualList})
+	if err != nil {
+		return ManualListResponse{}, err
+	}
+	var out ManualListResponse
+	err = rep.Decode(&out)
+	return out, err
+}
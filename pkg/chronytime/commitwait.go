@@ -0,0 +1,47 @@
+package chronytime
+
+import (
+	"context"
+	"time"
+)
+
+// Now returns the interval within which the current instant is guaranteed
+// to fall, per chronyd's current uncertainty bound: earliest and latest
+// bracket the true time the same way Response.Earliest brackets a single
+// Get call.
+func (c *Client) Now() (earliest, latest time.Time, err error) {
+	r, err := c.Get()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return r.Earliest(), r.Now.Add(r.Uncertainty), nil
+}
+
+// After reports whether t is definitely in chronyd's past, i.e. whether the
+// earliest the current instant could be is already after t. Combined with
+// CommitWait, this lets callers implement Spanner-style external
+// consistency without re-deriving the uncertainty bounds from raw
+// trackingResponse fields themselves.
+func (c *Client) After(t time.Time) (bool, error) {
+	earliest, _, err := c.Now()
+	if err != nil {
+		return false, err
+	}
+	return earliest.After(t), nil
+}
+
+// CommitWait runs prepare to obtain a commit timestamp for a distributed
+// transaction, then blocks until chronyd is sure the wall clock is past
+// commitTS, so any reader with a synced chrony daemon will see the write as
+// already having happened. Use context.WithTimeout to bound how long
+// CommitWait waits.
+func (c *Client) CommitWait(ctx context.Context, prepare func() (commitTS time.Time, err error)) (time.Time, error) {
+	commitTS, err := prepare()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := c.WaitUntilAfter(ctx, commitTS); err != nil {
+		return time.Time{}, err
+	}
+	return commitTS, nil
+}
@@ -0,0 +1,111 @@
+// Package exporter adapts a chronytime.Client into a Prometheus/OpenMetrics
+// collector, exposing the same tracking and per-source statistics that
+// `chronyc tracking` and `chronyc sourcestats` report so Kubernetes and
+// Prometheus users can alert on time-sync degradation using the same
+// client code they already use for ConsistentOperation.
+package exporter
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/vsekhar/chronytime/pkg/chronytime"
+)
+
+const namespace = "chrony"
+
+// Collector adapts a *chronytime.Client into a prometheus.Collector. Every
+// scrape issues a fresh Tracking request and, for each currently polled
+// source, a SourceStats request.
+type Collector struct {
+	client *chronytime.Client
+
+	rootDelay       *prometheus.Desc
+	rootDispersion  *prometheus.Desc
+	lastOffset      *prometheus.Desc
+	rmsOffset       *prometheus.Desc
+	frequencyPPM    *prometheus.Desc
+	skewPPM         *prometheus.Desc
+	stratum         *prometheus.Desc
+	leapStatus      *prometheus.Desc
+	uncertainty     *prometheus.Desc
+	sourceStdDev    *prometheus.Desc
+	sourceEstOffset *prometheus.Desc
+	sourceSkewPPM   *prometheus.Desc
+}
+
+// sourceLabels names the label attached to per-source metrics: the
+// chronyd-assigned source index, since the client has no reverse-DNS name
+// for a source's ipAddr.
+var sourceLabels = []string{"source_index"}
+
+// NewCollector returns a Collector wrapping client.
+func NewCollector(client *chronytime.Client) *Collector {
+	return &Collector{
+		client: client,
+
+		rootDelay:       prometheus.NewDesc(namespace+"_root_delay_seconds", "Total network delay to the stratum-1 reference clock.", nil, nil),
+		rootDispersion:  prometheus.NewDesc(namespace+"_root_dispersion_seconds", "Total dispersion accumulated through the chain to the stratum-1 reference clock.", nil, nil),
+		lastOffset:      prometheus.NewDesc(namespace+"_last_offset_seconds", "Estimated offset of the last clock update.", nil, nil),
+		rmsOffset:       prometheus.NewDesc(namespace+"_rms_offset_seconds", "Long-term RMS average of the clock offset.", nil, nil),
+		frequencyPPM:    prometheus.NewDesc(namespace+"_frequency_ppm", "Rate at which the system clock is adjusted relative to its true rate.", nil, nil),
+		skewPPM:         prometheus.NewDesc(namespace+"_skew_ppm", "Estimated error bound on the frequency.", nil, nil),
+		stratum:         prometheus.NewDesc(namespace+"_stratum", "Stratum of the reference chronyd is synchronized to.", nil, nil),
+		leapStatus:      prometheus.NewDesc(namespace+"_leap_status", "Pending leap second: 0 normal, 1 insert, 2 delete, 3 not synchronized.", nil, nil),
+		uncertainty:     prometheus.NewDesc(namespace+"_tracking_uncertainty_seconds", "Estimated uncertainty in chronyd's current clock correction.", nil, nil),
+		sourceStdDev:    prometheus.NewDesc(namespace+"_source_standard_deviation_seconds", "Estimated standard deviation of a source's clock offset.", sourceLabels, nil),
+		sourceEstOffset: prometheus.NewDesc(namespace+"_source_estimated_offset_seconds", "Estimated offset of a source's clock.", sourceLabels, nil),
+		sourceSkewPPM:   prometheus.NewDesc(namespace+"_source_skew_ppm", "Estimated error bound on a source's frequency.", sourceLabels, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.rootDelay
+	ch <- c.rootDispersion
+	ch <- c.lastOffset
+	ch <- c.rmsOffset
+	ch <- c.frequencyPPM
+	ch <- c.skewPPM
+	ch <- c.stratum
+	ch <- c.leapStatus
+	ch <- c.uncertainty
+	ch <- c.sourceStdDev
+	ch <- c.sourceEstOffset
+	ch <- c.sourceSkewPPM
+}
+
+// Collect implements prometheus.Collector. Scrape errors are dropped, as is
+// conventional for prometheus.Collector implementations: a scrape that
+// cannot reach chronyd simply contributes no samples.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	t, err := c.client.Tracking()
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.rootDelay, prometheus.GaugeValue, t.RootDelay.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.rootDispersion, prometheus.GaugeValue, t.RootDispersion.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.lastOffset, prometheus.GaugeValue, t.LastOffset.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.rmsOffset, prometheus.GaugeValue, t.RmsOffset.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.frequencyPPM, prometheus.GaugeValue, t.FreqPPM)
+	ch <- prometheus.MustNewConstMetric(c.skewPPM, prometheus.GaugeValue, t.SkewPPM)
+	ch <- prometheus.MustNewConstMetric(c.stratum, prometheus.GaugeValue, float64(t.Stratum))
+	ch <- prometheus.MustNewConstMetric(c.leapStatus, prometheus.GaugeValue, float64(t.LeapStatus))
+	ch <- prometheus.MustNewConstMetric(c.uncertainty, prometheus.GaugeValue, t.Uncertainty.Seconds())
+
+	n, err := c.client.NumSources()
+	if err != nil {
+		return
+	}
+	for i := 0; i < n; i++ {
+		s, err := c.client.SourceStats(i)
+		if err != nil {
+			continue
+		}
+		label := []string{strconv.Itoa(i)}
+		ch <- prometheus.MustNewConstMetric(c.sourceStdDev, prometheus.GaugeValue, s.StandardDeviation.Value(), label...)
+		ch <- prometheus.MustNewConstMetric(c.sourceEstOffset, prometheus.GaugeValue, s.EstimatedOffset.Value(), label...)
+		ch <- prometheus.MustNewConstMetric(c.sourceSkewPPM, prometheus.GaugeValue, s.SkewPPM.Value(), label...)
+	}
+}
@@ -0,0 +1,91 @@
+package chronytime
+
+// HLC is a Hybrid Logical Clock timestamp: a physical component derived
+// from chronyd's uncertainty-bounded wall clock, a logical counter that
+// orders events landing within the same uncertainty window, and the
+// uncertainty bound the physical component was read under. Two events
+// ordered via HLCNow/Update remain correctly ordered even when they fall
+// inside the same chrony uncertainty window, analogous to the
+// sequence-number ordering the Go runtime trace adopted to avoid tick
+// collisions.
+type HLC struct {
+	PhysicalNanos    uint64
+	Logical          uint32
+	UncertaintyNanos uint64
+}
+
+// HLCNow returns the next Hybrid Logical Clock timestamp for c. If the
+// current chrony-derived physical time is strictly greater than the
+// previously issued one, the logical counter resets to 0; otherwise the
+// previous physical component is reused and the logical counter is
+// incremented, so that two calls landing in the same uncertainty window
+// still order distinctly.
+func (c *Client) HLCNow() (HLC, error) {
+	r, err := c.Get()
+	if err != nil {
+		return HLC{}, err
+	}
+	physical := uint64(r.Now.UnixNano())
+
+	c.hlcMu.Lock()
+	defer c.hlcMu.Unlock()
+	if physical > c.hlcPhysical {
+		c.hlcPhysical = physical
+		c.hlcLogical = 0
+	} else {
+		physical = c.hlcPhysical
+		c.hlcLogical++
+	}
+	return HLC{
+		PhysicalNanos:    physical,
+		Logical:          c.hlcLogical,
+		UncertaintyNanos: uint64(r.Uncertainty.Nanoseconds()),
+	}, nil
+}
+
+// Update advances c's Hybrid Logical Clock state to be causally after
+// remote: the physical component becomes max(local, remote, wall), and the
+// logical counter is set to max(local.Logical, remote.Logical)+1 when the
+// physicals tie, to the Logical of whichever of local/remote alone supplied
+// the new maximum plus one, or to 0 when the wall clock alone supplies it.
+func (c *Client) Update(remote HLC) (HLC, error) {
+	r, err := c.Get()
+	if err != nil {
+		return HLC{}, err
+	}
+	wall := uint64(r.Now.UnixNano())
+
+	c.hlcMu.Lock()
+	defer c.hlcMu.Unlock()
+
+	maxPhysical := wall
+	if c.hlcPhysical > maxPhysical {
+		maxPhysical = c.hlcPhysical
+	}
+	if remote.PhysicalNanos > maxPhysical {
+		maxPhysical = remote.PhysicalNanos
+	}
+
+	localTied := maxPhysical == c.hlcPhysical
+	remoteTied := maxPhysical == remote.PhysicalNanos
+	switch {
+	case localTied && remoteTied:
+		if remote.Logical > c.hlcLogical {
+			c.hlcLogical = remote.Logical
+		}
+		c.hlcLogical++
+	case localTied:
+		c.hlcLogical++
+	case remoteTied:
+		c.hlcLogical = remote.Logical + 1
+	default:
+		c.hlcLogical = 0
+	}
+	c.hlcPhysical = maxPhysical
+
+	return HLC{
+		PhysicalNanos:    c.hlcPhysical,
+		Logical:          c.hlcLogical,
+		UncertaintyNanos: uint64(r.Uncertainty.Nanoseconds()),
+	}, nil
+}
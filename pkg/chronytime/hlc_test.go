@@ -0,0 +1,162 @@
+package chronytime
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// fakeHLCTransport answers every tracking request with a fixed reply, so
+// HLCNow/Update can be driven deterministically without a real chronyd.
+type fakeHLCTransport struct{}
+
+func (fakeHLCTransport) roundTrip(req []byte) ([]byte, error) {
+	var hdr struct {
+		Version, PktType, Res1, Res2 uint8
+		Command, Attempt             uint16
+		Sequence                     uint32
+	}
+	if err := binary.Read(bytes.NewReader(req), networkOrder, &hdr); err != nil {
+		return nil, err
+	}
+	rep := response{
+		Version:  6,
+		PktType:  pktTypeCmdReply,
+		Command:  cmdTracking,
+		Reply:    rpyTracking,
+		Status:   sttSuccess,
+		Sequence: hdr.Sequence,
+	}
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, networkOrder, rep); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (fakeHLCTransport) Close() error { return nil }
+
+func newHLCTestClient() *Client {
+	return &Client{transport: fakeHLCTransport{}}
+}
+
+// farFuture is far past any wall-clock reading HLCNow/Update will observe
+// during the test, so it always wins ties against the real clock and lets
+// the reset/increment logic be driven deterministically.
+const farFuture = uint64(1) << 62
+
+func TestHLCNowResetsOnAdvance(t *testing.T) {
+	c := newHLCTestClient()
+
+	first, err := c.HLCNow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Logical != 0 {
+		t.Errorf("expected logical 0 on first call, got %d", first.Logical)
+	}
+
+	second, err := c.HLCNow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.PhysicalNanos <= first.PhysicalNanos {
+		t.Errorf("expected physical to advance: %d then %d", first.PhysicalNanos, second.PhysicalNanos)
+	}
+	if second.Logical != 0 {
+		t.Errorf("expected logical to reset to 0 when physical advances, got %d", second.Logical)
+	}
+}
+
+func TestHLCNowIncrementsWithinSameWindow(t *testing.T) {
+	c := newHLCTestClient()
+	c.hlcPhysical = farFuture
+	c.hlcLogical = 5
+
+	got, err := c.HLCNow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PhysicalNanos != farFuture {
+		t.Errorf("expected physical to stay at %d, got %d", farFuture, got.PhysicalNanos)
+	}
+	if got.Logical != 6 {
+		t.Errorf("expected logical to increment to 6, got %d", got.Logical)
+	}
+}
+
+func TestUpdateLocalWins(t *testing.T) {
+	c := newHLCTestClient()
+	c.hlcPhysical = farFuture
+	c.hlcLogical = 3
+
+	remote := HLC{PhysicalNanos: farFuture / 2, Logical: 9}
+	got, err := c.Update(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PhysicalNanos != farFuture {
+		t.Errorf("expected local physical %d to win, got %d", farFuture, got.PhysicalNanos)
+	}
+	if got.Logical != 4 {
+		t.Errorf("expected logical to increment from local's 3 to 4, got %d", got.Logical)
+	}
+}
+
+func TestUpdateRemoteWins(t *testing.T) {
+	c := newHLCTestClient()
+	c.hlcPhysical = farFuture / 2
+	c.hlcLogical = 3
+
+	remote := HLC{PhysicalNanos: farFuture, Logical: 9}
+	got, err := c.Update(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PhysicalNanos != farFuture {
+		t.Errorf("expected remote physical %d to win, got %d", farFuture, got.PhysicalNanos)
+	}
+	if got.Logical != 10 {
+		t.Errorf("expected logical to carry forward from remote's 9 to 10, got %d", got.Logical)
+	}
+}
+
+func TestUpdateTieTakesMaxLogicalPlusOne(t *testing.T) {
+	c := newHLCTestClient()
+	c.hlcPhysical = farFuture
+	c.hlcLogical = 3
+
+	remote := HLC{PhysicalNanos: farFuture, Logical: 9}
+	got, err := c.Update(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PhysicalNanos != farFuture {
+		t.Errorf("expected physical to stay at %d, got %d", farFuture, got.PhysicalNanos)
+	}
+	if got.Logical != 10 {
+		t.Errorf("expected logical max(3, 9)+1 = 10, got %d", got.Logical)
+	}
+}
+
+func TestUpdateWallWinsResetsLogical(t *testing.T) {
+	c := newHLCTestClient()
+	c.hlcPhysical = 1
+	c.hlcLogical = 7
+
+	remote := HLC{PhysicalNanos: 2, Logical: 11}
+	got, err := c.Update(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PhysicalNanos == 1 || got.PhysicalNanos == 2 {
+		t.Errorf("expected wall clock to dominate local/remote physicals, got %d", got.PhysicalNanos)
+	}
+	if got.Logical != 0 {
+		t.Errorf("expected logical to reset to 0 when wall wins, got %d", got.Logical)
+	}
+	if got.PhysicalNanos > math.MaxInt64 {
+		t.Errorf("expected physical to be a plausible UnixNano value, got %d", got.PhysicalNanos)
+	}
+}
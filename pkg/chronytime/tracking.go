@@ -0,0 +1,52 @@
+package chronytime
+
+import "time"
+
+// duration converts a cfloat holding a quantity of seconds, as most chrony
+// replies do, into a time.Duration.
+func (f *cfloat) duration() time.Duration {
+	return time.Duration(f.value() * float64(time.Second))
+}
+
+// TrackingResponse is the decoded, exported form of chronyd's tracking
+// reply, as reported by `chronyc tracking`.
+type TrackingResponse struct {
+	RefID             uint32
+	Stratum           uint16
+	LeapStatus        uint16
+	RefTime           time.Time
+	CurrentCorrection time.Duration
+	LastOffset        time.Duration
+	RmsOffset         time.Duration
+	FreqPPM           float64
+	SkewPPM           float64
+	RootDelay         time.Duration
+	RootDispersion    time.Duration
+	Uncertainty       time.Duration
+}
+
+// Tracking returns chronyd's current clock tracking statistics, as reported
+// by `chronyc tracking`. Unlike Get, which reports only the corrected wall
+// time and its uncertainty, Tracking exposes the full set of fields chrony
+// dashboards and health checks typically want.
+func (c *Client) Tracking() (TrackingResponse, error) {
+	rep, err := c.trackingRequest()
+	if err != nil {
+		return TrackingResponse{}, err
+	}
+	t := rep.Tracking
+	return TrackingResponse{
+		RefID:             t.RefID,
+		Stratum:           t.Stratum,
+		LeapStatus:        t.LeapStatus,
+		RefTime:           t.RefTime.Time(),
+		CurrentCorrection: t.CurrentCorrection.duration(),
+		LastOffset:        t.LastOffset.duration(),
+		RmsOffset:         t.RmsOffset.duration(),
+		FreqPPM:           t.FreqPPM.value(),
+		SkewPPM:           t.SkewPPM.value(),
+		RootDelay:         t.RootDelay.duration(),
+		RootDispersion:    t.RootDispersion.duration(),
+		Uncertainty:       uncertaintyFromCorrectedTime(t),
+	}, nil
+}
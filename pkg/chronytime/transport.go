@@ -0,0 +1,190 @@
+package chronytime
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultUnixSocket is available if we are running as the chrony user or
+// root. Regular users will connect via UDP instead.
+const defaultUnixSocket = "/var/run/chrony/chronyd.sock"
+
+// defaultReadTimeout bounds how long a request waits for chronyd to reply.
+const defaultReadTimeout = 1 * time.Second
+
+// Network selects the transport a Client uses to reach chronyd.
+type Network string
+
+const (
+	// NetworkUDP4 dials chronyd's command port over IPv4 UDP. This is the
+	// default and works for any user, but modern chrony configs disable it.
+	NetworkUDP4 Network = "udp4"
+	// NetworkUDP6 dials chronyd's command port over IPv6 UDP.
+	NetworkUDP6 Network = "udp6"
+	// NetworkUnix connects to chronyd's UNIX domain socket, which requires
+	// running as root or the chrony user but is enabled by default, and is
+	// the only transport chronyd accepts privileged commands such as
+	// MakeStep, Burst, SetTime and Shutdown over.
+	NetworkUnix Network = "unix"
+)
+
+// transport sends a chrony command packet and returns the raw reply bytes.
+// It abstracts over UDP and UNIX domain socket connections so Do and
+// trackingRequest can speak either one identically.
+type transport interface {
+	roundTrip(req []byte) ([]byte, error)
+	io.Closer
+}
+
+type udpTransport struct {
+	conn    *net.UDPConn
+	addr    *net.UDPAddr
+	timeout time.Duration
+}
+
+func (t *udpTransport) roundTrip(req []byte) ([]byte, error) {
+	if _, err := t.conn.Write(req); err != nil {
+		return nil, err
+	}
+	buffer := make([]byte, 1024)
+	t.conn.SetDeadline(time.Now().Add(t.timeout))
+	n, addr, err := t.conn.ReadFromUDP(buffer)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("empty read")
+	}
+	if !sameUDPAddr(*addr, *t.addr) {
+		return nil, fmt.Errorf("expected %+v, got %+v", *t.addr, *addr)
+	}
+	return buffer[:n], nil
+}
+
+func (t *udpTransport) Close() error {
+	return t.conn.Close()
+}
+
+func sameUDPAddr(a1, a2 net.UDPAddr) bool {
+	if a1.IP.Equal(a2.IP) &&
+		a1.Port == a2.Port &&
+		a1.Zone == a2.Zone {
+		return true
+	}
+	return false
+}
+
+// unixTransport talks to chronyd's UNIX domain command socket, which
+// chronyd binds as SOCK_DGRAM rather than SOCK_STREAM. Like chronyc, this
+// requires binding our own client path so chronyd has somewhere to send its
+// reply datagram back to; chronyd authorizes the request by that socket
+// file's permissions, not by any credentials passed on the wire.
+type unixTransport struct {
+	conn      *net.UnixConn
+	localPath string
+	timeout   time.Duration
+}
+
+func (t *unixTransport) roundTrip(req []byte) ([]byte, error) {
+	if _, err := t.conn.Write(req); err != nil {
+		return nil, err
+	}
+	buffer := make([]byte, 1024)
+	t.conn.SetDeadline(time.Now().Add(t.timeout))
+	n, err := t.conn.Read(buffer)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("empty read")
+	}
+	return buffer[:n], nil
+}
+
+func (t *unixTransport) Close() error {
+	err := t.conn.Close()
+	os.Remove(t.localPath)
+	return err
+}
+
+// bindUnixgramClient returns a fresh address in the temp directory for the
+// client end of a unixgram transport to chronyd. It does not bind the socket
+// itself; the caller's DialUnix binds it so chronyd can reply to it.
+func bindUnixgramClient() (*net.UnixAddr, error) {
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("chronytime-%d-%d.sock", os.Getpid(), rand.Uint32()))
+	return &net.UnixAddr{Name: path, Net: "unixgram"}, nil
+}
+
+// options holds the configuration assembled by a NewClientWithOptions call.
+type options struct {
+	network Network
+	address string
+	timeout time.Duration
+}
+
+func defaultOptions() options {
+	return options{
+		network: NetworkUDP4,
+		address: fmt.Sprintf("127.0.0.1:%d", defaultCandMPort),
+		timeout: defaultReadTimeout,
+	}
+}
+
+// Option configures a Client created by NewClientWithOptions.
+type Option func(*options)
+
+// WithNetwork selects the transport used to reach chronyd. The default is
+// NetworkUDP4; use NetworkUnix to talk to defaultUnixSocket (or a path set
+// via WithAddress) when running as root or the chrony user.
+func WithNetwork(n Network) Option {
+	return func(o *options) { o.network = n }
+}
+
+// WithAddress overrides the address Client dials: a host:port for
+// NetworkUDP4/NetworkUDP6, or a socket path for NetworkUnix. If unset, a
+// NetworkUnix client dials defaultUnixSocket.
+func WithAddress(addr string) Option {
+	return func(o *options) { o.address = addr }
+}
+
+// WithTimeout overrides how long a request waits for chronyd to reply.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+func dialTransport(o options) (transport, error) {
+	switch o.network {
+	case NetworkUnix:
+		addr := o.address
+		if addr == "" {
+			addr = defaultUnixSocket
+		}
+		local, err := bindUnixgramClient()
+		if err != nil {
+			return nil, err
+		}
+		conn, err := net.DialUnix("unixgram", local, &net.UnixAddr{Name: addr, Net: "unixgram"})
+		if err != nil {
+			os.Remove(local.Name)
+			return nil, err
+		}
+		return &unixTransport{conn: conn, localPath: local.Name, timeout: o.timeout}, nil
+	case NetworkUDP4, NetworkUDP6:
+		s, err := net.ResolveUDPAddr(string(o.network), o.address)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := net.DialUDP(string(o.network), nil, s)
+		if err != nil {
+			return nil, err
+		}
+		return &udpTransport{conn: conn, addr: s, timeout: o.timeout}, nil
+	default:
+		return nil, fmt.Errorf("unsupported network %q", o.network)
+	}
+}
@@ -0,0 +1,68 @@
+package chronytime
+
+import (
+	"context"
+	"time"
+)
+
+// syncedThreshold is the uncertainty below which chronyd is considered to
+// be synchronized, matching the threshold the non-context client used to
+// wait out before returning from NewClient.
+const syncedThreshold = 20 * time.Millisecond
+
+// TrackingSample is one snapshot delivered by Client.Watch: a decoded
+// tracking Response, whether chronyd currently appears to be synchronized,
+// and any error encountered obtaining this particular sample. If Err is
+// non-nil, Response and Synced are zero values.
+type TrackingSample struct {
+	Response
+	Synced bool
+	Err    error
+}
+
+// Watch polls chronyd at the given interval and delivers a TrackingSample
+// on the returned channel for each poll, letting consumers - dashboards,
+// health checks, adaptive commit-wait tuners - react to skew and dispersion
+// changes without each writing their own poll loop around the raw binary
+// protocol.
+//
+// The first sample is fetched synchronously so a connection error surfaces
+// immediately as Watch's returned error rather than silently starting a
+// channel nobody reads from. The channel is closed when ctx is done.
+func (c *Client) Watch(ctx context.Context, interval time.Duration) (<-chan TrackingSample, error) {
+	first, err := c.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan TrackingSample, 1)
+	ch <- TrackingSample{Response: first, Synced: first.Uncertainty <= syncedThreshold}
+
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sample := TrackingSample{}
+				r, err := c.Get()
+				if err != nil {
+					sample.Err = err
+				} else {
+					sample.Response = r
+					sample.Synced = r.Uncertainty <= syncedThreshold
+				}
+				select {
+				case ch <- sample:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
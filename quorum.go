@@ -0,0 +1,151 @@
+package chronytime
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrNoQuorum is returned by QuorumClient.Get when no point in time is
+// covered by the uncertainty intervals of at least f+1 of the configured
+// endpoints. It carries the individual responses so the caller can decide
+// whether to fall back to a single-source Client.
+type ErrNoQuorum struct {
+	Responses []TrackingResponse
+}
+
+func (e *ErrNoQuorum) Error() string {
+	return fmt.Sprintf("no quorum among %d chronyd responses", len(e.Responses))
+}
+
+// Response is a time reading derived from a quorum of chronyd endpoints.
+type Response struct {
+	Now         time.Time
+	Uncertainty time.Duration
+}
+
+// Earliest returns the earliest time at which the Response could have been
+// obtained.
+func (r Response) Earliest() time.Time {
+	return r.Now.Add(-r.Uncertainty)
+}
+
+// Latest returns the latest time at which the Response could have been
+// obtained.
+func (r Response) Latest() time.Time {
+	return r.Now.Add(r.Uncertainty)
+}
+
+// QuorumClient fans a single Get out to several independent chronyd
+// endpoints - peer machines, or independent stratum-1 boxes - and combines
+// their responses using a Marzullo-style interval intersection. This
+// addresses the single-point-of-trust weakness of Client, where a single
+// misbehaving local chronyd can silently violate the uncertainty bound.
+type QuorumClient struct {
+	clients []*Client
+	f       int
+}
+
+// NewQuorumClient builds a QuorumClient from clients, one per chronyd
+// endpoint, tolerating up to f of them being faulty, lying, or unreachable.
+// A quorum therefore requires agreement from at least f+1 endpoints.
+func NewQuorumClient(clients []*Client, f int) *QuorumClient {
+	return &QuorumClient{clients: clients, f: f}
+}
+
+// Get fans a Tracking request out to every configured endpoint, waiting at
+// most timeout in total, and intersects the resulting uncertainty intervals.
+// If the largest overlap is covered by fewer than f+1 endpoints, Get returns
+// an *ErrNoQuorum carrying whatever responses it did receive.
+func (q *QuorumClient) Get(timeout time.Duration) (Response, error) {
+	type result struct {
+		t   TrackingResponse
+		err error
+	}
+	results := make(chan result, len(q.clients))
+	for _, c := range q.clients {
+		c := c
+		go func() {
+			t, err := c.Tracking()
+			results <- result{t: t, err: err}
+		}()
+	}
+
+	deadline := time.After(timeout)
+	responses := make([]TrackingResponse, 0, len(q.clients))
+collect:
+	for range q.clients {
+		select {
+		case r := <-results:
+			if r.err == nil {
+				responses = append(responses, r.t)
+			}
+		case <-deadline:
+			break collect
+		}
+	}
+
+	now := time.Now()
+	intervals := make([]interval, len(responses))
+	for i, r := range responses {
+		wall := now.Add(r.CurrentCorrection)
+		intervals[i] = interval{earliest: wall.Add(-r.Uncertainty), latest: wall.Add(r.Uncertainty)}
+	}
+
+	start, end, count := bestOverlap(intervals)
+	if count < q.f+1 {
+		return Response{}, &ErrNoQuorum{Responses: responses}
+	}
+
+	width := end.Sub(start)
+	return Response{Now: start.Add(width / 2), Uncertainty: width / 2}, nil
+}
+
+// interval is a closed time interval [earliest, latest].
+type interval struct {
+	earliest, latest time.Time
+}
+
+// bestOverlap sweeps the given intervals and returns the bounds and
+// coverage count of the sub-interval covered by the largest number of them,
+// per Marzullo's algorithm. If intervals is empty, count is 0.
+func bestOverlap(intervals []interval) (start, end time.Time, count int) {
+	type event struct {
+		t     time.Time
+		start bool
+	}
+	events := make([]event, 0, 2*len(intervals))
+	for _, iv := range intervals {
+		events = append(events, event{t: iv.earliest, start: true}, event{t: iv.latest, start: false})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].t.Equal(events[j].t) {
+			// Process starts before ends so that touching intervals are
+			// counted as overlapping at the shared instant.
+			return events[i].start && !events[j].start
+		}
+		return events[i].t.Before(events[j].t)
+	})
+
+	running := 0
+	best := 0
+	for i, e := range events {
+		if e.start {
+			running++
+		} else {
+			running--
+		}
+		if running > best {
+			best = running
+			start = e.t
+			end = e.t
+			for j := i + 1; j < len(events); j++ {
+				if !events[j].start {
+					end = events[j].t
+					break
+				}
+			}
+		}
+	}
+	return start, end, best
+}
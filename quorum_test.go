@@ -0,0 +1,106 @@
+package chronytime
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// fakeTrackingTransport is a transport stub that answers every request with
+// a fixed tracking reply, echoing back whatever sequence number the request
+// carried. It lets QuorumClient.Get be tested without a real chronyd.
+type fakeTrackingTransport struct {
+	tracking trackingResponse
+
+	calls int
+}
+
+func (f *fakeTrackingTransport) roundTrip(req []byte) ([]byte, error) {
+	f.calls++
+	var hdr struct {
+		Version, PktType, Res1, Res2 uint8
+		Command, Attempt             uint16
+		Sequence                     uint32
+	}
+	if err := binary.Read(bytes.NewReader(req), networkOrder, &hdr); err != nil {
+		return nil, err
+	}
+	rep := response{
+		Version:  6,
+		PktType:  pktTypeCmdReply,
+		Command:  cmdTracking,
+		Reply:    rpyTracking,
+		Status:   sttSuccess,
+		Sequence: hdr.Sequence,
+		Tracking: f.tracking,
+	}
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, networkOrder, rep); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (f *fakeTrackingTransport) Close() error { return nil }
+
+// TestQuorumClientGet exercises QuorumClient.Get end to end against three
+// fake endpoints whose corrected wall-clock readings differ slightly but
+// whose (non-zero) uncertainty windows overlap, confirming Get actually
+// forms a quorum rather than only exercising bestOverlap in isolation.
+func TestQuorumClientGet(t *testing.T) {
+	// Each tracking reply carries RootDelay=0.012432915s and
+	// RootDispersion=0.001648686s, giving each endpoint an uncertainty of
+	// about 7.87ms plus the magnitude of its correction (7.97ms to 8.17ms
+	// for the corrections below), comfortably wider than their spread.
+	rootDelay := cfloat{F: -154422419}
+	rootDispersion := cfloat{F: -254273351}
+	corrections := []cfloat{
+		{F: -355354857}, // ~0.0002s
+		{F: -382842647}, // ~-0.0001s
+		{F: -325236398}, // ~0.0003s
+	}
+
+	clients := make([]*Client, len(corrections))
+	for i, corr := range corrections {
+		clients[i] = &Client{transport: &fakeTrackingTransport{tracking: trackingResponse{
+			CurrentCorrection: corr,
+			RootDelay:         rootDelay,
+			RootDispersion:    rootDispersion,
+		}}}
+	}
+
+	q := NewQuorumClient(clients, 1)
+	resp, err := q.Get(time.Second)
+	if err != nil {
+		t.Fatalf("expected quorum, got error: %v", err)
+	}
+	if resp.Uncertainty <= 0 {
+		t.Errorf("expected non-degenerate uncertainty, got %s", resp.Uncertainty)
+	}
+}
+
+func TestBestOverlap(t *testing.T) {
+	base := time.Unix(1600000000, 0)
+	intervals := []interval{
+		{earliest: base, latest: base.Add(10 * time.Second)},
+		{earliest: base.Add(5 * time.Second), latest: base.Add(15 * time.Second)},
+		{earliest: base.Add(20 * time.Second), latest: base.Add(30 * time.Second)},
+	}
+
+	start, end, count := bestOverlap(intervals)
+	if count != 2 {
+		t.Fatalf("expected count 2, got %d", count)
+	}
+	wantStart := base.Add(5 * time.Second)
+	wantEnd := base.Add(10 * time.Second)
+	if !start.Equal(wantStart) || !end.Equal(wantEnd) {
+		t.Errorf("expected [%s, %s], got [%s, %s]", wantStart, wantEnd, start, end)
+	}
+}
+
+func TestBestOverlapNoIntervals(t *testing.T) {
+	if _, _, count := bestOverlap(nil); count != 0 {
+		t.Errorf("expected count 0, got %d", count)
+	}
+}